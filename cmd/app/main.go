@@ -3,9 +3,16 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"inkwell-backend-V2.0/internal/llm"
+	_ "inkwell-backend-V2.0/internal/llm/anthropic"
+	_ "inkwell-backend-V2.0/internal/llm/gemini"
+	_ "inkwell-backend-V2.0/internal/llm/openai"
 	"io"
 	"log"
 	"math/rand"
@@ -13,14 +20,19 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/common-nighthawk/go-figure"
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-contrib/sessions/redis"
 	"github.com/gin-gonic/gin"
 
 	"inkwell-backend-V2.0/internal/config"
 	"inkwell-backend-V2.0/internal/db"
+	"inkwell-backend-V2.0/internal/logging"
 	"inkwell-backend-V2.0/internal/model"
 	"inkwell-backend-V2.0/internal/repository"
 	"inkwell-backend-V2.0/internal/service"
@@ -28,7 +40,72 @@ import (
 )
 
 var ollamaCmd *exec.Cmd // Store the Ollama process
-var ollamaClient *llm.OllamaClient
+
+const (
+	oauthStateCookieName = "oauth_state"
+	oauthStateTTL        = 5 * time.Minute
+	sessionCookieTTL     = 24 * time.Hour
+)
+
+// newSessionStore builds the gin-contrib/sessions store described by cfg: a
+// Redis-backed store when cfg.Store is "redis", a signed cookie store
+// otherwise.
+func newSessionStore(cfg config.SessionConfig) sessions.Store {
+	var store sessions.Store
+
+	if cfg.Store == "redis" {
+		redisStore, err := redis.NewStore(10, "tcp", cfg.RedisAddr, "", []byte(cfg.Secret))
+		if err != nil {
+			log.Fatalf("failed to connect to redis session store: %v", err)
+		}
+		store = redisStore
+	} else {
+		store = cookie.NewStore([]byte(cfg.Secret))
+	}
+
+	store.Options(sessions.Options{
+		Path:     "/",
+		MaxAge:   cfg.MaxAge,
+		Secure:   cfg.Secure,
+		HttpOnly: true,
+		SameSite: parseSameSite(cfg.SameSite),
+	})
+
+	return store
+}
+
+// parseSameSite maps a config.xml SameSite string to its http.SameSite
+// value, defaulting to Lax.
+func parseSameSite(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// newOAuthState generates an unguessable value used both as the OAuth state
+// parameter and the cookie it's checked against, protecting the callback
+// from CSRF.
+func newOAuthState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := cryptorand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// grammarTopics are the topics a new assessment is randomly drawn from.
+var grammarTopics = []string{
+	"Tenses",
+	"Subject-Verb Agreement",
+	"Active and Passive Voice",
+	"Direct and Indirect Speech",
+	"Punctuation Rules",
+}
 
 func main() {
 	// Load XML configuration from file.
@@ -40,19 +117,27 @@ func main() {
 	printStartUpBanner()
 	// Initialize DB using the loaded config.
 	db.InitDBFromConfig(cfg)
-	startOllama()
 
-	// Wait until Ollama is responsive before proceeding
-	waitForOllama()
+	providerCfg, err := cfg.ActiveProvider()
+	if err != nil {
+		log.Fatalf("failed to resolve active llm provider: %v", err)
+	}
 
-	// Initialize Ollama Client
-	ollamaClient = llm.NewOllamaClient("http://localhost:11434/api/generate")
+	// The managed Ollama subprocess is only relevant when Ollama itself is
+	// the active provider and it's pointed at a local endpoint.
+	if providerCfg.Type == "ollama" && isLocalEndpoint(providerCfg.Endpoint) {
+		startOllama()
+		waitForOllama()
+		preloadModel(providerCfg.Model)
+	}
 
-	// Preload the model
-	preloadModel("mistral")
+	llmProvider, err := llm.Get(providerCfg.Type, providerCfg.Endpoint, providerCfg.Model, providerCfg.APIKey)
+	if err != nil {
+		log.Fatalf("failed to initialize llm provider: %v", err)
+	}
 
 	// Run migrations.
-	err = db.GetDB().AutoMigrate(&model.User{}, &model.Assessment{}, &model.Question{}, &model.Answer{}, &model.Story{})
+	err = db.GetDB().AutoMigrate(&model.User{}, &model.Assessment{}, &model.Question{}, &model.Answer{}, &model.Story{}, &model.OAuthIdentity{})
 	if err != nil {
 		log.Fatalf("AutoMigration Error: %v", err)
 		return
@@ -62,22 +147,42 @@ func main() {
 	userRepo := repository.NewUserRepository()
 	assessmentRepo := repository.NewAssessmentRepository()
 	storyRepo := repository.NewStoryRepository()
+	oauthIdentityRepo := repository.NewOAuthIdentityRepository()
+
+	// inFlightCtx is the root context threaded into services that call out to
+	// the LLM provider. Canceling it (on shutdown, once the drain window
+	// elapses) aborts any of those calls still in flight.
+	inFlightCtx, cancelInFlight := context.WithCancel(context.Background())
+	defer cancelInFlight()
 
 	// Create services.
 	authService := service.NewAuthService(userRepo)
 	userService := service.NewUserService(userRepo)
-	assessmentService := service.NewAssessmentService(assessmentRepo, ollamaClient)
+	assessmentService := service.NewAssessmentService(assessmentRepo, llmProvider, inFlightCtx)
+	oauthService := service.NewOAuthService(cfg.Auth.OAuth, userRepo, oauthIdentityRepo, cfg.Auth.JWTSecret)
 
 	storyService := service.NewStoryService(storyRepo)
 
+	logger := logging.New(cfg.Logging)
+
 	// Initialize Gin router.
 	r := gin.Default()
 
+	// Request id + structured access logging; installed first so every other
+	// middleware and handler can pull the request-scoped logger from c.
+	r.Use(utilities.RequestLogger(logger))
+
 	// CORS configuration.
 	r.Use(utilities.CORSMiddleware())
 
+	// Server-side sessions, backing both login state and CSRF tokens.
+	r.Use(sessions.Sessions(cfg.Auth.Session.CookieName, newSessionStore(cfg.Auth.Session)))
+
 	//Authentication middleware
-	r.Use(utilities.AuthMiddleware())
+	r.Use(utilities.AuthMiddleware(cfg.Auth.JWTSecret))
+
+	// CSRF protection for state-changing requests.
+	r.Use(utilities.CSRFMiddleware())
 
 	// Auth routes.
 	auth := r.Group("/auth")
@@ -109,7 +214,98 @@ func main() {
 				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 				return
 			}
-			c.JSON(http.StatusOK, user)
+
+			csrfToken, err := utilities.NewCSRFToken()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start session"})
+				return
+			}
+
+			session := sessions.Default(c)
+			session.Set(utilities.SessionUserIDKey, user.ID)
+			session.Set(utilities.SessionRoleKey, user.Role)
+			session.Set(utilities.CSRFSessionKey, csrfToken)
+			if err := session.Save(); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start session"})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{"user": user, "csrf_token": csrfToken})
+		})
+
+		auth.POST("/logout", func(c *gin.Context) {
+			session := sessions.Default(c)
+			session.Clear()
+			if err := session.Save(); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+				return
+			}
+			c.SetCookie(utilities.SessionCookieName, "", -1, "/", "", false, true)
+			c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+		})
+
+		// Redirect to the provider's authorize URL, with a state cookie the
+		// callback uses to rule out CSRF. The cookie holds the raw random
+		// state value; it is not signed.
+		auth.GET("/oauth/:provider/login", func(c *gin.Context) {
+			state, err := newOAuthState()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start OAuth login"})
+				return
+			}
+
+			authorizeURL, err := oauthService.AuthorizeURL(c.Param("provider"), state)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+
+			c.SetCookie(oauthStateCookieName, state, int(oauthStateTTL.Seconds()), "/", "", false, true)
+			c.Redirect(http.StatusFound, authorizeURL)
+		})
+
+		// Exchange the authorization code, upsert the user, and set the session cookie.
+		auth.GET("/oauth/:provider/callback", func(c *gin.Context) {
+			state := c.Query("state")
+			cookieState, err := c.Cookie(oauthStateCookieName)
+			c.SetCookie(oauthStateCookieName, "", -1, "/", "", false, true)
+			if err != nil || cookieState == "" || cookieState != state {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid OAuth state"})
+				return
+			}
+
+			user, err := oauthService.HandleCallback(c.Request.Context(), c.Param("provider"), c.Query("code"))
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+
+			token, err := oauthService.IssueSessionToken(user)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+				return
+			}
+
+			// OAuth users authenticate via the inkwell_session JWT cookie rather
+			// than auth.POST("/login"), so they need the same session CSRF
+			// token set here or CSRFMiddleware rejects their first request.
+			csrfToken, err := utilities.NewCSRFToken()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+				return
+			}
+
+			session := sessions.Default(c)
+			session.Set(utilities.SessionUserIDKey, user.ID)
+			session.Set(utilities.SessionRoleKey, user.Role)
+			session.Set(utilities.CSRFSessionKey, csrfToken)
+			if err := session.Save(); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+				return
+			}
+
+			c.SetCookie(utilities.SessionCookieName, token, int(sessionCookieTTL.Seconds()), "/", "", false, true)
+			c.JSON(http.StatusOK, gin.H{"user": user, "csrf_token": csrfToken})
 		})
 	}
 
@@ -127,19 +323,12 @@ func main() {
 	assessmentRoutes := r.Group("/assessments")
 	{
 		assessmentRoutes.POST("/start", func(c *gin.Context) {
-			grammarTopics := []string{
-				"Tenses",
-				"Subject-Verb Agreement",
-				"Active and Passive Voice",
-				"Direct and Indirect Speech",
-				"Punctuation Rules",
-			}
-
 			src := rand.NewSource(time.Now().UnixNano())
 			ra := rand.New(src)
 			selectedTopic := grammarTopics[ra.Intn(len(grammarTopics))]
 
-			assessment, questions, err := assessmentService.CreateAssessment(selectedTopic)
+			ctx := llm.WithRequestID(c.Request.Context(), utilities.RequestID(c))
+			assessment, questions, err := assessmentService.CreateAssessment(ctx, selectedTopic)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 				return
@@ -190,8 +379,8 @@ func main() {
 			}
 
 			if !questionBelongsToAssessment {
-				log.Printf("Assessment Questions: %+v", assessment.Questions)
-				log.Printf("Submitted Question ID: %d", req.QuestionID)
+				utilities.LoggerFromContext(c).Warn("submitted question does not belong to assessment",
+					"session_id", req.SessionID, "question_id", req.QuestionID)
 
 				c.JSON(http.StatusForbidden, gin.H{"error": "Question does not belong to this assessment"})
 				return
@@ -217,7 +406,7 @@ func main() {
 
 			answerResponse, err := assessmentService.SaveAnswer(&answer)
 			if err != nil {
-				log.Printf("Failed to save answer: %v", err)
+				utilities.LoggerFromContext(c).Error("failed to save answer", "error", err)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save answer"})
 				return
 			}
@@ -226,6 +415,163 @@ func main() {
 			c.JSON(http.StatusOK, answerResponse)
 		})
 
+		// Start an assessment, streaming the generated questions as they're produced
+		assessmentRoutes.POST("/start/stream", func(c *gin.Context) {
+			src := rand.NewSource(time.Now().UnixNano())
+			ra := rand.New(src)
+			selectedTopic := grammarTopics[ra.Intn(len(grammarTopics))]
+
+			ctx := llm.WithRequestID(c.Request.Context(), utilities.RequestID(c))
+			chunks, err := assessmentService.StreamQuestions(ctx, selectedTopic)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+
+			c.Header("Content-Type", "text/event-stream")
+			c.Header("Cache-Control", "no-cache")
+			c.Header("Connection", "keep-alive")
+
+			var full strings.Builder
+			var streamErr error
+
+			c.Stream(func(w io.Writer) bool {
+				chunk, ok := <-chunks
+				if !ok {
+					return false
+				}
+				if chunk.Err != nil {
+					streamErr = chunk.Err
+					return false
+				}
+				full.WriteString(chunk.Text)
+				fmt.Fprintf(w, "data: %s\n\n", chunk.Text)
+				return !chunk.Done
+			})
+
+			if streamErr != nil {
+				utilities.LoggerFromContext(c).Error("assessment question stream failed", "error", streamErr)
+				return
+			}
+
+			assessment, questions, err := assessmentService.FinalizeAssessment(selectedTopic, full.String())
+			if err != nil {
+				utilities.LoggerFromContext(c).Error("failed to finalize streamed assessment", "error", err)
+				return
+			}
+
+			questionIDs := make([]uint, len(questions))
+			var correctAnswers strings.Builder
+			for i, q := range questions {
+				questionIDs[i] = q.ID
+				correctAnswers.WriteString(q.CorrectAnswer)
+			}
+			answersHash := sha256.Sum256([]byte(correctAnswers.String()))
+
+			c.SSEvent("done", gin.H{
+				"session_id":   assessment.SessionID,
+				"question_ids": questionIDs,
+				"answers_hash": hex.EncodeToString(answersHash[:]),
+			})
+			c.Writer.Flush()
+		})
+
+		// Submit an answer, streaming the LLM's explanation of the verdict
+		assessmentRoutes.POST("/submit/stream", func(c *gin.Context) {
+			var req struct {
+				SessionID  string `json:"session_id" binding:"required"`
+				QuestionID uint   `json:"question_id" binding:"required"`
+				Answer     string `json:"answer" binding:"required"`
+			}
+
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: missing required fields"})
+				return
+			}
+
+			assessment, err := assessmentService.GetAssessmentBySessionID(req.SessionID)
+			if err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+				return
+			}
+
+			question, err := assessmentRepo.GetQuestionByID(req.QuestionID)
+			if err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+				return
+			}
+
+			var questionBelongsToAssessment bool
+			for _, q := range assessment.Questions {
+				if q.ID == question.ID {
+					questionBelongsToAssessment = true
+					break
+				}
+			}
+
+			if !questionBelongsToAssessment {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Question does not belong to this assessment"})
+				return
+			}
+
+			isCorrect := question.CorrectAnswer == req.Answer
+
+			ctx := llm.WithRequestID(c.Request.Context(), utilities.RequestID(c))
+			chunks, err := assessmentService.StreamFeedback(ctx, question, req.Answer, isCorrect)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+
+			c.Header("Content-Type", "text/event-stream")
+			c.Header("Cache-Control", "no-cache")
+			c.Header("Connection", "keep-alive")
+
+			var full strings.Builder
+			var streamErr error
+
+			c.Stream(func(w io.Writer) bool {
+				chunk, ok := <-chunks
+				if !ok {
+					return false
+				}
+				if chunk.Err != nil {
+					streamErr = chunk.Err
+					return false
+				}
+				full.WriteString(chunk.Text)
+				fmt.Fprintf(w, "data: %s\n\n", chunk.Text)
+				return !chunk.Done
+			})
+
+			if streamErr != nil {
+				utilities.LoggerFromContext(c).Error("feedback stream failed", "error", streamErr)
+				return
+			}
+
+			answer := model.Answer{
+				AssessmentID: assessment.ID,
+				SessionID:    req.SessionID,
+				QuestionID:   req.QuestionID,
+				UserID:       assessment.UserID,
+				Answer:       req.Answer,
+				IsCorrect:    isCorrect,
+				Feedback:     full.String(),
+			}
+
+			answerResponse, err := assessmentService.SaveAnswer(&answer)
+			if err != nil {
+				utilities.LoggerFromContext(c).Error("failed to save answer", "error", err)
+				return
+			}
+
+			c.SSEvent("done", gin.H{
+				"answer_id":  answerResponse.ID,
+				"is_correct": isCorrect,
+			})
+			c.Writer.Flush()
+		})
+
 		// Get a specific assessment
 		assessmentRoutes.GET("/:session_id", func(c *gin.Context) {
 			sessionID := c.Param("session_id")
@@ -248,25 +594,56 @@ func main() {
 		}
 		c.JSON(http.StatusOK, stories)
 	})
+	addr := fmt.Sprintf("%s:%d", cfg.Context.Host, cfg.Context.Port)
+	srv := &http.Server{Addr: addr, Handler: r}
+
 	// **Graceful shutdown handling in a separate goroutine**
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
 
+	// done is closed once the shutdown goroutine has finished draining and
+	// cleaning up, so main can block on it instead of racing the goroutine
+	// to process exit.
+	done := make(chan struct{})
+
 	go func() {
+		defer close(done)
+
 		<-signalChan
 		log.Println("Received termination signal. Shutting down gracefully...")
 
+		// Stop accepting new connections and give in-flight requests (and the
+		// LLM calls they've made) up to ShutdownTimeout to finish.
+		shutdownTimeout := time.Duration(cfg.Context.ShutdownTimeout) * time.Second
+		if shutdownTimeout <= 0 {
+			shutdownTimeout = 10 * time.Second
+		}
+		shutdownCtx, cancelTimeout := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancelTimeout()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Server shutdown did not complete cleanly: %v", err)
+		}
+
+		// Cancel any LLM calls still in flight once the drain window elapses.
+		cancelInFlight()
+
+		if err := db.Close(); err != nil {
+			log.Printf("Failed to close database: %v", err)
+		}
+
 		stopOllama()
 
 		log.Println("Application shut down successfully.")
-		os.Exit(0)
 	}()
 
-	// Start the server
-	addr := fmt.Sprintf("%s:%d", cfg.Context.Host, cfg.Context.Port)
-	if err := r.Run(addr); err != nil {
+	// Start the server. ListenAndServe returns http.ErrServerClosed as soon
+	// as srv.Shutdown closes the listener, so wait for the goroutine above to
+	// finish draining and cleaning up before main returns.
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		log.Fatalf("Server failed: %v", err)
 	}
+	<-done
 }
 
 func printStartUpBanner() {
@@ -310,6 +687,13 @@ func startOllama() {
 	log.Println("Ollama started successfully")
 }
 
+// isLocalEndpoint reports whether endpoint points at this machine, which is
+// the only case where it makes sense for us to manage the Ollama subprocess
+// ourselves.
+func isLocalEndpoint(endpoint string) bool {
+	return strings.Contains(endpoint, "localhost") || strings.Contains(endpoint, "127.0.0.1")
+}
+
 // Check if Ollama is already running
 func isOllamaRunning() bool {
 	resp, err := http.Get("http://localhost:11434")
@@ -357,13 +741,34 @@ func preloadModel(modelName string) {
 	}
 }
 
+// stopOllamaTimeout is how long stopOllama waits for a SIGTERM'd Ollama to
+// exit on its own before escalating to SIGKILL.
+const stopOllamaTimeout = 5 * time.Second
+
 // Stop Ollama on shutdown
 func stopOllama() {
-	if ollamaCmd != nil {
-		log.Println("Stopping Ollama...")
-		err := ollamaCmd.Process.Signal(syscall.SIGTERM)
+	if ollamaCmd == nil || ollamaCmd.Process == nil {
+		return
+	}
+
+	log.Println("Stopping Ollama...")
+	if err := ollamaCmd.Process.Signal(syscall.SIGTERM); err != nil {
+		log.Printf("Failed to send SIGTERM to Ollama: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- ollamaCmd.Wait() }()
+
+	select {
+	case err := <-done:
 		if err != nil {
-			log.Printf("Failed to stop Ollama: %v", err)
+			log.Printf("Ollama exited with error: %v", err)
+		}
+	case <-time.After(stopOllamaTimeout):
+		log.Println("Ollama did not exit after SIGTERM, killing it")
+		if err := ollamaCmd.Process.Kill(); err != nil {
+			log.Printf("Failed to kill Ollama: %v", err)
 		}
+		<-done
 	}
 }
@@ -0,0 +1,49 @@
+// Package authtoken issues and verifies the JWTs Inkwell uses to represent a
+// logged-in session, shared by both the OAuth callback (which issues them)
+// and the auth middleware (which verifies them).
+package authtoken
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims is the payload of an Inkwell session JWT.
+type Claims struct {
+	UserID uint   `json:"user_id"`
+	Role   string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// Issue signs a session JWT for userID/role that expires after ttl.
+func Issue(secret string, userID uint, role string, ttl time.Duration) (string, error) {
+	claims := Claims{
+		UserID: userID,
+		Role:   role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+// Parse verifies tokenString's signature and expiry and returns its claims.
+func Parse(secret, tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{"HS256"}))
+	if err != nil {
+		return nil, fmt.Errorf("invalid session token: %w", err)
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid session token")
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,113 @@
+// Package config loads Inkwell's XML configuration file.
+package config
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+)
+
+// Config is the root of config.xml.
+type Config struct {
+	XMLName  xml.Name       `xml:"config"`
+	Context  ContextConfig  `xml:"context"`
+	Database DatabaseConfig `xml:"database"`
+	LLM      LLMConfig      `xml:"llm"`
+	Auth     AuthConfig     `xml:"auth"`
+	Logging  LoggingConfig  `xml:"logging"`
+}
+
+// ContextConfig controls the HTTP server.
+type ContextConfig struct {
+	Host string `xml:"host"`
+	Port int    `xml:"port"`
+	// ShutdownTimeout is how many seconds to wait for in-flight requests to
+	// drain during a graceful shutdown before forcing them closed.
+	ShutdownTimeout int `xml:"shutdownTimeout"`
+}
+
+// DatabaseConfig controls the GORM connection.
+type DatabaseConfig struct {
+	Driver string `xml:"driver"`
+	DSN    string `xml:"dsn"`
+}
+
+// LLMConfig lists the available LLM providers and which one is active.
+type LLMConfig struct {
+	ActiveProvider string           `xml:"activeProvider"`
+	Providers      []ProviderConfig `xml:"providers>provider"`
+}
+
+// ProviderConfig describes a single configured LLM provider.
+type ProviderConfig struct {
+	Name     string `xml:"name,attr"`
+	Type     string `xml:"type"` // ollama, openai, anthropic, gemini
+	Endpoint string `xml:"endpoint"`
+	Model    string `xml:"model"`
+	APIKey   string `xml:"apiKey"`
+}
+
+// AuthConfig controls session signing, external identity providers, and
+// server-side session storage.
+type AuthConfig struct {
+	JWTSecret string                `xml:"jwtSecret"`
+	OAuth     []OAuthProviderConfig `xml:"oauth>provider"`
+	Session   SessionConfig         `xml:"session"`
+}
+
+// SessionConfig controls the gin-contrib/sessions store backing server-side
+// sessions and CSRF tokens.
+type SessionConfig struct {
+	CookieName string `xml:"cookieName"`
+	Secret     string `xml:"secret"`
+	MaxAge     int    `xml:"maxAge"`
+	Secure     bool   `xml:"secure"`
+	SameSite   string `xml:"sameSite"` // lax, strict, or none
+	Store      string `xml:"store"`    // cookie (default) or redis
+	RedisAddr  string `xml:"redisAddr"`
+}
+
+// OAuthProviderConfig describes one external identity provider Inkwell can
+// log users in through (e.g. Google, GitHub).
+type OAuthProviderConfig struct {
+	Name         string   `xml:"name,attr"`
+	ClientID     string   `xml:"clientId"`
+	ClientSecret string   `xml:"clientSecret"`
+	IssuerURL    string   `xml:"issuerUrl"`
+	AuthURL      string   `xml:"authUrl"`
+	TokenURL     string   `xml:"tokenUrl"`
+	UserInfoURL  string   `xml:"userInfoUrl"`
+	RedirectURL  string   `xml:"redirectUrl"`
+	Scopes       []string `xml:"scopes>scope"`
+}
+
+// LoggingConfig controls the request logging middleware.
+type LoggingConfig struct {
+	// JSON selects structured JSON log lines; when false, logs are plain text.
+	JSON bool `xml:"json"`
+}
+
+// LoadConfig reads and parses the XML configuration file at path.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg Config
+	if err := xml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// ActiveProvider returns the ProviderConfig named by LLM.ActiveProvider.
+func (c *Config) ActiveProvider() (ProviderConfig, error) {
+	for _, p := range c.LLM.Providers {
+		if p.Name == c.LLM.ActiveProvider {
+			return p, nil
+		}
+	}
+	return ProviderConfig{}, fmt.Errorf("no llm provider configured with name %q", c.LLM.ActiveProvider)
+}
@@ -0,0 +1,42 @@
+// Package db manages the process-wide GORM connection.
+package db
+
+import (
+	"log"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"inkwell-backend-V2.0/internal/config"
+)
+
+var dbInstance *gorm.DB
+
+// InitDBFromConfig opens a SQLite database at cfg.Database.DSN and stores it
+// for later retrieval via GetDB. cfg.Database.Driver is not consulted.
+func InitDBFromConfig(cfg *config.Config) {
+	dialect := sqlite.Open(cfg.Database.DSN)
+
+	conn, err := gorm.Open(dialect, &gorm.Config{})
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+
+	dbInstance = conn
+}
+
+// GetDB returns the process-wide GORM connection initialized by
+// InitDBFromConfig.
+func GetDB() *gorm.DB {
+	return dbInstance
+}
+
+// Close closes the underlying connection pool. Call it once the server has
+// stopped accepting new requests and in-flight ones have drained.
+func Close() error {
+	sqlDB, err := dbInstance.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
@@ -0,0 +1,184 @@
+// Package anthropic implements llm.Provider against the Anthropic Messages
+// API.
+package anthropic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"inkwell-backend-V2.0/internal/llm"
+)
+
+func init() {
+	llm.Register("anthropic", func(endpoint, model, apiKey string) llm.Provider {
+		return NewClient(endpoint, model, apiKey)
+	})
+}
+
+const anthropicVersion = "2023-06-01"
+
+// Client talks to the Anthropic Messages API.
+type Client struct {
+	endpoint string
+	model    string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewClient builds a Client against endpoint (e.g.
+// "https://api.anthropic.com") using model as the default model.
+func NewClient(endpoint, model, apiKey string) *Client {
+	return &Client{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		model:    model,
+		apiKey:   apiKey,
+		client:   &http.Client{},
+	}
+}
+
+type messagesRequest struct {
+	Model     string    `json:"model"`
+	Messages  []message `json:"messages"`
+	MaxTokens int       `json:"max_tokens"`
+	Stream    bool      `json:"stream"`
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type contentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type messagesResponse struct {
+	Content []contentBlock `json:"content"`
+}
+
+type streamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+}
+
+func (c *Client) modelOrDefault(model string) string {
+	if model != "" {
+		return model
+	}
+	return c.model
+}
+
+func (c *Client) newRequest(ctx context.Context, req llm.GenerateRequest, stream bool) (*http.Request, error) {
+	body, err := json.Marshal(messagesRequest{
+		Model:     c.modelOrDefault(req.Model),
+		Messages:  []message{{Role: "user", Content: req.Prompt}},
+		MaxTokens: 4096,
+		Stream:    stream,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal anthropic request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build anthropic request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+	httpReq.Header.Set("x-api-key", c.apiKey)
+	return httpReq, nil
+}
+
+// Generate sends a non-streaming message request.
+func (c *Client) Generate(ctx context.Context, req llm.GenerateRequest) (llm.GenerateResponse, error) {
+	httpReq, err := c.newRequest(ctx, req, false)
+	if err != nil {
+		return llm.GenerateResponse{}, err
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return llm.GenerateResponse{}, fmt.Errorf("anthropic request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := llm.CheckStatus("anthropic", resp); err != nil {
+		return llm.GenerateResponse{}, err
+	}
+
+	var out messagesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return llm.GenerateResponse{}, fmt.Errorf("failed to decode anthropic response: %w", err)
+	}
+	if len(out.Content) == 0 {
+		return llm.GenerateResponse{}, fmt.Errorf("anthropic response had no content")
+	}
+
+	return llm.GenerateResponse{Text: out.Content[0].Text}, nil
+}
+
+// Stream sends a streaming message request and forwards each
+// content_block_delta event as a Chunk.
+func (c *Client) Stream(ctx context.Context, req llm.GenerateRequest) (<-chan llm.Chunk, error) {
+	httpReq, err := c.newRequest(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic request failed: %w", err)
+	}
+	if err := llm.CheckStatus("anthropic", resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	chunks := make(chan llm.Chunk)
+
+	go func() {
+		defer close(chunks)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+
+			var evt streamEvent
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &evt); err != nil {
+				chunks <- llm.Chunk{Err: fmt.Errorf("failed to decode anthropic event: %w", err)}
+				return
+			}
+
+			switch evt.Type {
+			case "content_block_delta":
+				chunks <- llm.Chunk{Text: evt.Delta.Text}
+			case "message_stop":
+				chunks <- llm.Chunk{Done: true}
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			chunks <- llm.Chunk{Err: fmt.Errorf("anthropic stream read failed: %w", err)}
+		}
+	}()
+
+	return chunks, nil
+}
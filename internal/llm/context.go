@@ -0,0 +1,21 @@
+package llm
+
+import "context"
+
+type contextKey string
+
+const requestIDContextKey contextKey = "request_id"
+
+// WithRequestID attaches a request id to ctx so Provider implementations can
+// forward it upstream (e.g. as an X-Request-ID header), making it possible
+// to trace an assessment generation across the whole stack.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+// RequestIDFromContext returns the request id attached by WithRequestID, or
+// "" if none was set.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
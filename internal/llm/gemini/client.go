@@ -0,0 +1,168 @@
+// Package gemini implements llm.Provider against the Google Gemini
+// generateContent API.
+package gemini
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"inkwell-backend-V2.0/internal/llm"
+)
+
+func init() {
+	llm.Register("gemini", func(endpoint, model, apiKey string) llm.Provider {
+		return NewClient(endpoint, model, apiKey)
+	})
+}
+
+// Client talks to the Gemini generateContent / streamGenerateContent API.
+type Client struct {
+	endpoint string
+	model    string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewClient builds a Client against endpoint (e.g.
+// "https://generativelanguage.googleapis.com") using model as the default
+// model (e.g. "gemini-1.5-flash").
+func NewClient(endpoint, model, apiKey string) *Client {
+	return &Client{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		model:    model,
+		apiKey:   apiKey,
+		client:   &http.Client{},
+	}
+}
+
+type genPart struct {
+	Text string `json:"text"`
+}
+
+type genContent struct {
+	Parts []genPart `json:"parts"`
+}
+
+type generateRequest struct {
+	Contents []genContent `json:"contents"`
+}
+
+type genCandidate struct {
+	Content genContent `json:"content"`
+}
+
+type generateResponse struct {
+	Candidates []genCandidate `json:"candidates"`
+}
+
+func (c *Client) modelOrDefault(model string) string {
+	if model != "" {
+		return model
+	}
+	return c.model
+}
+
+func (c *Client) url(model, method string) string {
+	return fmt.Sprintf("%s/v1beta/models/%s:%s?key=%s", c.endpoint, c.modelOrDefault(model), method, c.apiKey)
+}
+
+// Generate sends a non-streaming generateContent request.
+func (c *Client) Generate(ctx context.Context, req llm.GenerateRequest) (llm.GenerateResponse, error) {
+	body, err := json.Marshal(generateRequest{Contents: []genContent{{Parts: []genPart{{Text: req.Prompt}}}}})
+	if err != nil {
+		return llm.GenerateResponse{}, fmt.Errorf("failed to marshal gemini request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(req.Model, "generateContent"), bytes.NewReader(body))
+	if err != nil {
+		return llm.GenerateResponse{}, fmt.Errorf("failed to build gemini request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return llm.GenerateResponse{}, fmt.Errorf("gemini request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := llm.CheckStatus("gemini", resp); err != nil {
+		return llm.GenerateResponse{}, err
+	}
+
+	var out generateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return llm.GenerateResponse{}, fmt.Errorf("failed to decode gemini response: %w", err)
+	}
+	if len(out.Candidates) == 0 || len(out.Candidates[0].Content.Parts) == 0 {
+		return llm.GenerateResponse{}, fmt.Errorf("gemini response had no candidates")
+	}
+
+	return llm.GenerateResponse{Text: out.Candidates[0].Content.Parts[0].Text}, nil
+}
+
+// Stream sends a streamGenerateContent request with alt=sse and forwards the
+// text of each SSE "data:" event from the response as a Chunk.
+func (c *Client) Stream(ctx context.Context, req llm.GenerateRequest) (<-chan llm.Chunk, error) {
+	body, err := json.Marshal(generateRequest{Contents: []genContent{{Parts: []genPart{{Text: req.Prompt}}}}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal gemini request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(req.Model, "streamGenerateContent")+"&alt=sse", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build gemini request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("gemini request failed: %w", err)
+	}
+	if err := llm.CheckStatus("gemini", resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	chunks := make(chan llm.Chunk)
+
+	go func() {
+		defer close(chunks)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+
+			var out generateResponse
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &out); err != nil {
+				chunks <- llm.Chunk{Err: fmt.Errorf("failed to decode gemini chunk: %w", err)}
+				return
+			}
+			if len(out.Candidates) == 0 || len(out.Candidates[0].Content.Parts) == 0 {
+				continue
+			}
+			chunks <- llm.Chunk{Text: out.Candidates[0].Content.Parts[0].Text}
+		}
+
+		if err := scanner.Err(); err != nil {
+			chunks <- llm.Chunk{Err: fmt.Errorf("gemini stream read failed: %w", err)}
+			return
+		}
+		chunks <- llm.Chunk{Done: true}
+	}()
+
+	return chunks, nil
+}
@@ -0,0 +1,21 @@
+package llm
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// CheckStatus returns an error describing resp's body when resp isn't a 2xx,
+// so a provider's real error (bad key, bad model, rate limit, ...) surfaces
+// instead of a generic "decode failed"/"no content" error from trying to
+// unmarshal an error body as a success response. provider is the backend
+// name (e.g. "openai") used to label the error.
+func CheckStatus(provider string, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("%s request failed with status %d: %s", provider, resp.StatusCode, body)
+}
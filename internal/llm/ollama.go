@@ -0,0 +1,153 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func init() {
+	Register("ollama", func(endpoint, model, apiKey string) Provider {
+		return NewOllamaClient(endpoint, model)
+	})
+}
+
+// OllamaClient talks to a local Ollama server's /api/generate endpoint. It
+// implements Provider.
+type OllamaClient struct {
+	endpoint string
+	model    string
+	client   *http.Client
+}
+
+// NewOllamaClient builds an OllamaClient against endpoint, the Ollama
+// server's base URL (e.g. "http://localhost:11434"), using model as the
+// default model when a GenerateRequest doesn't specify one.
+func NewOllamaClient(endpoint, model string) *OllamaClient {
+	return &OllamaClient{
+		endpoint: strings.TrimRight(endpoint, "/") + "/api/generate",
+		model:    model,
+		client:   &http.Client{},
+	}
+}
+
+func (c *OllamaClient) modelOrDefault(model string) string {
+	if model != "" {
+		return model
+	}
+	return c.model
+}
+
+type ollamaRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Stream bool   `json:"stream"`
+}
+
+type ollamaResponse struct {
+	Response string `json:"response"`
+	Done     bool   `json:"done"`
+}
+
+// Generate sends a non-streaming request and waits for the full response.
+func (c *OllamaClient) Generate(ctx context.Context, req GenerateRequest) (GenerateResponse, error) {
+	body, err := json.Marshal(ollamaRequest{Model: c.modelOrDefault(req.Model), Prompt: req.Prompt, Stream: false})
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("failed to marshal ollama request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("failed to build ollama request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		httpReq.Header.Set("X-Request-ID", requestID)
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("ollama request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := CheckStatus("ollama", resp); err != nil {
+		return GenerateResponse{}, err
+	}
+
+	var out ollamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return GenerateResponse{}, fmt.Errorf("failed to decode ollama response: %w", err)
+	}
+
+	return GenerateResponse{Text: out.Response}, nil
+}
+
+// Stream sends a streaming request and forwards each NDJSON line from Ollama
+// as a Chunk. The returned channel is closed once Ollama reports done, the
+// context is canceled, or an error occurs.
+func (c *OllamaClient) Stream(ctx context.Context, req GenerateRequest) (<-chan Chunk, error) {
+	body, err := json.Marshal(ollamaRequest{Model: c.modelOrDefault(req.Model), Prompt: req.Prompt, Stream: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ollama request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build ollama request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		httpReq.Header.Set("X-Request-ID", requestID)
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("ollama request failed: %w", err)
+	}
+	if err := CheckStatus("ollama", resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	chunks := make(chan Chunk)
+
+	go func() {
+		defer close(chunks)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			line := scanner.Bytes()
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+
+			var chunk ollamaResponse
+			if err := json.Unmarshal(line, &chunk); err != nil {
+				chunks <- Chunk{Err: fmt.Errorf("failed to decode ollama chunk: %w", err)}
+				return
+			}
+
+			chunks <- Chunk{Text: chunk.Response, Done: chunk.Done}
+			if chunk.Done {
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			chunks <- Chunk{Err: fmt.Errorf("ollama stream read failed: %w", err)}
+		}
+	}()
+
+	return chunks, nil
+}
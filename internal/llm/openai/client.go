@@ -0,0 +1,177 @@
+// Package openai implements llm.Provider against any OpenAI-compatible chat
+// completions endpoint. This covers OpenAI itself, Groq, together.ai, and
+// Ollama's own /v1 compatibility layer — they all speak the same wire
+// format, so one client serves all of them.
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"inkwell-backend-V2.0/internal/llm"
+)
+
+func init() {
+	llm.Register("openai", func(endpoint, model, apiKey string) llm.Provider {
+		return NewClient(endpoint, model, apiKey)
+	})
+}
+
+// Client talks to an OpenAI-compatible /chat/completions endpoint.
+type Client struct {
+	endpoint string
+	model    string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewClient builds a Client against endpoint (the provider's base URL, e.g.
+// "https://api.openai.com/v1") using model as the default model.
+func NewClient(endpoint, model, apiKey string) *Client {
+	return &Client{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		model:    model,
+		apiKey:   apiKey,
+		client:   &http.Client{},
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+}
+
+type chatChoice struct {
+	Delta   chatMessage `json:"delta"`
+	Message chatMessage `json:"message"`
+}
+
+type chatResponse struct {
+	Choices []chatChoice `json:"choices"`
+}
+
+func (c *Client) modelOrDefault(model string) string {
+	if model != "" {
+		return model
+	}
+	return c.model
+}
+
+func (c *Client) newRequest(ctx context.Context, req llm.GenerateRequest, stream bool) (*http.Request, error) {
+	body, err := json.Marshal(chatRequest{
+		Model:    c.modelOrDefault(req.Model),
+		Messages: []chatMessage{{Role: "user", Content: req.Prompt}},
+		Stream:   stream,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal openai request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build openai request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	return httpReq, nil
+}
+
+// Generate sends a non-streaming chat completion request.
+func (c *Client) Generate(ctx context.Context, req llm.GenerateRequest) (llm.GenerateResponse, error) {
+	httpReq, err := c.newRequest(ctx, req, false)
+	if err != nil {
+		return llm.GenerateResponse{}, err
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return llm.GenerateResponse{}, fmt.Errorf("openai request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := llm.CheckStatus("openai", resp); err != nil {
+		return llm.GenerateResponse{}, err
+	}
+
+	var out chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return llm.GenerateResponse{}, fmt.Errorf("failed to decode openai response: %w", err)
+	}
+	if len(out.Choices) == 0 {
+		return llm.GenerateResponse{}, fmt.Errorf("openai response had no choices")
+	}
+
+	return llm.GenerateResponse{Text: out.Choices[0].Message.Content}, nil
+}
+
+// Stream sends a streaming chat completion request and forwards each SSE
+// "data:" line from the provider as a Chunk.
+func (c *Client) Stream(ctx context.Context, req llm.GenerateRequest) (<-chan llm.Chunk, error) {
+	httpReq, err := c.newRequest(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openai request failed: %w", err)
+	}
+	if err := llm.CheckStatus("openai", resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	chunks := make(chan llm.Chunk)
+
+	go func() {
+		defer close(chunks)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if payload == "[DONE]" {
+				chunks <- llm.Chunk{Done: true}
+				return
+			}
+
+			var out chatResponse
+			if err := json.Unmarshal([]byte(payload), &out); err != nil {
+				chunks <- llm.Chunk{Err: fmt.Errorf("failed to decode openai chunk: %w", err)}
+				return
+			}
+			if len(out.Choices) == 0 {
+				continue
+			}
+			chunks <- llm.Chunk{Text: out.Choices[0].Delta.Content}
+		}
+
+		if err := scanner.Err(); err != nil {
+			chunks <- llm.Chunk{Err: fmt.Errorf("openai stream read failed: %w", err)}
+		}
+	}()
+
+	return chunks, nil
+}
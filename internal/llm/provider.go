@@ -0,0 +1,37 @@
+// Package llm defines the provider-agnostic interface Inkwell's services use
+// to talk to whichever LLM backend is active, plus a registry that resolves
+// the configured provider name to an implementation.
+package llm
+
+import "context"
+
+// GenerateRequest is a single completion request sent to a Provider.
+type GenerateRequest struct {
+	Model  string
+	Prompt string
+}
+
+// GenerateResponse is the full text returned by a non-streaming Generate call.
+type GenerateResponse struct {
+	Text string
+}
+
+// Chunk is one piece of a streamed response.
+type Chunk struct {
+	Text string
+	Done bool
+	Err  error
+}
+
+// Provider is implemented by every LLM backend Inkwell can talk to: Ollama,
+// OpenAI-compatible endpoints (OpenAI, Groq, together.ai, Ollama's /v1 API),
+// Anthropic, and Gemini.
+type Provider interface {
+	// Generate returns the full response once the model has finished.
+	Generate(ctx context.Context, req GenerateRequest) (GenerateResponse, error)
+	// Stream returns a channel of Chunks as the model produces them. The
+	// channel is closed after a Chunk with Done set to true or Err set; it
+	// is also closed with no such final Chunk when ctx is canceled or the
+	// upstream response ends without a terminating event.
+	Stream(ctx context.Context, req GenerateRequest) (<-chan Chunk, error)
+}
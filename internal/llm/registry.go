@@ -0,0 +1,27 @@
+package llm
+
+import "fmt"
+
+// Factory builds a Provider from its ProviderConfig-derived fields. It is
+// kept decoupled from internal/config so that llm has no dependency on the
+// config package.
+type Factory func(endpoint, model, apiKey string) Provider
+
+var factories = map[string]Factory{}
+
+// Register associates a provider type name (e.g. "ollama", "openai",
+// "anthropic", "gemini") with a Factory. Provider packages call this from an
+// init() function so that main only needs to import them for side effects.
+func Register(providerType string, factory Factory) {
+	factories[providerType] = factory
+}
+
+// Get builds the Provider registered for providerType, or an error if no
+// provider of that type has been registered.
+func Get(providerType, endpoint, model, apiKey string) (Provider, error) {
+	factory, ok := factories[providerType]
+	if !ok {
+		return nil, fmt.Errorf("no llm provider registered for type %q", providerType)
+	}
+	return factory(endpoint, model, apiKey), nil
+}
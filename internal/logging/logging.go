@@ -0,0 +1,21 @@
+// Package logging builds Inkwell's process-wide structured logger.
+package logging
+
+import (
+	"log/slog"
+	"os"
+
+	"inkwell-backend-V2.0/internal/config"
+)
+
+// New builds the request logger described by cfg: JSON output when cfg.JSON
+// is set, human-readable text otherwise.
+func New(cfg config.LoggingConfig) *slog.Logger {
+	var handler slog.Handler
+	if cfg.JSON {
+		handler = slog.NewJSONHandler(os.Stdout, nil)
+	} else {
+		handler = slog.NewTextHandler(os.Stdout, nil)
+	}
+	return slog.New(handler)
+}
@@ -0,0 +1,58 @@
+// Package model defines the GORM-backed domain types shared across Inkwell's
+// repositories and services.
+package model
+
+import "gorm.io/gorm"
+
+// User is a registered Inkwell account.
+type User struct {
+	gorm.Model
+	Email    string `gorm:"uniqueIndex" json:"email"`
+	AuthHash string `json:"-"`
+	Role     string `json:"role"`
+}
+
+// Assessment is a single grammar assessment session.
+type Assessment struct {
+	gorm.Model
+	SessionID string     `gorm:"uniqueIndex" json:"session_id"`
+	UserID    uint       `json:"user_id"`
+	Topic     string     `json:"topic"`
+	Questions []Question `json:"questions"`
+}
+
+// Question is one question belonging to an Assessment.
+type Question struct {
+	gorm.Model
+	AssessmentID  uint   `json:"assessment_id"`
+	Text          string `json:"text"`
+	CorrectAnswer string `json:"-"`
+}
+
+// Answer records a user's submitted answer to a Question.
+type Answer struct {
+	gorm.Model
+	AssessmentID uint   `json:"assessment_id"`
+	SessionID    string `json:"session_id"`
+	QuestionID   uint   `json:"question_id"`
+	UserID       uint   `json:"user_id"`
+	Answer       string `json:"answer"`
+	IsCorrect    bool   `json:"is_correct"`
+	Feedback     string `json:"feedback"`
+}
+
+// OAuthIdentity links a User to their account with an external identity
+// provider (e.g. Google, GitHub).
+type OAuthIdentity struct {
+	gorm.Model
+	Provider string `gorm:"uniqueIndex:idx_oauth_provider_subject" json:"provider"`
+	Subject  string `gorm:"uniqueIndex:idx_oauth_provider_subject" json:"subject"`
+	UserID   uint   `json:"user_id"`
+}
+
+// Story is a piece of reading content shown in the Stories section.
+type Story struct {
+	gorm.Model
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"inkwell-backend-V2.0/internal/db"
+	"inkwell-backend-V2.0/internal/model"
+)
+
+// AssessmentRepository provides persistence for assessments, their
+// questions, and submitted answers.
+type AssessmentRepository struct{}
+
+// NewAssessmentRepository builds an AssessmentRepository backed by the
+// process-wide DB.
+func NewAssessmentRepository() *AssessmentRepository {
+	return &AssessmentRepository{}
+}
+
+// Create inserts a new assessment along with its questions.
+func (r *AssessmentRepository) Create(assessment *model.Assessment) error {
+	return db.GetDB().Create(assessment).Error
+}
+
+// GetBySessionID loads an assessment and its questions by session id.
+func (r *AssessmentRepository) GetBySessionID(sessionID string) (*model.Assessment, error) {
+	var assessment model.Assessment
+	if err := db.GetDB().Preload("Questions").Where("session_id = ?", sessionID).First(&assessment).Error; err != nil {
+		return nil, err
+	}
+	return &assessment, nil
+}
+
+// GetQuestionByID loads a single question by primary key.
+func (r *AssessmentRepository) GetQuestionByID(id uint) (*model.Question, error) {
+	var question model.Question
+	if err := db.GetDB().First(&question, id).Error; err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// SaveAnswer inserts a submitted answer.
+func (r *AssessmentRepository) SaveAnswer(answer *model.Answer) (*model.Answer, error) {
+	if err := db.GetDB().Create(answer).Error; err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
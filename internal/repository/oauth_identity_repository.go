@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"inkwell-backend-V2.0/internal/db"
+	"inkwell-backend-V2.0/internal/model"
+)
+
+// OAuthIdentityRepository provides persistence for model.OAuthIdentity.
+type OAuthIdentityRepository struct{}
+
+// NewOAuthIdentityRepository builds an OAuthIdentityRepository backed by the
+// process-wide DB.
+func NewOAuthIdentityRepository() *OAuthIdentityRepository {
+	return &OAuthIdentityRepository{}
+}
+
+// Create inserts a new identity linking a provider subject to a user.
+func (r *OAuthIdentityRepository) Create(identity *model.OAuthIdentity) error {
+	return db.GetDB().Create(identity).Error
+}
+
+// FindByProviderSubject looks up an identity by provider and subject.
+func (r *OAuthIdentityRepository) FindByProviderSubject(provider, subject string) (*model.OAuthIdentity, error) {
+	var identity model.OAuthIdentity
+	if err := db.GetDB().Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
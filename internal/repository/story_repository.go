@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"inkwell-backend-V2.0/internal/db"
+	"inkwell-backend-V2.0/internal/model"
+)
+
+// StoryRepository provides persistence for model.Story.
+type StoryRepository struct{}
+
+// NewStoryRepository builds a StoryRepository backed by the process-wide DB.
+func NewStoryRepository() *StoryRepository {
+	return &StoryRepository{}
+}
+
+// FindAll returns every story.
+func (r *StoryRepository) FindAll() ([]model.Story, error) {
+	var stories []model.Story
+	if err := db.GetDB().Find(&stories).Error; err != nil {
+		return nil, err
+	}
+	return stories, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"inkwell-backend-V2.0/internal/db"
+	"inkwell-backend-V2.0/internal/model"
+)
+
+// UserRepository provides persistence for model.User.
+type UserRepository struct{}
+
+// NewUserRepository builds a UserRepository backed by the process-wide DB.
+func NewUserRepository() *UserRepository {
+	return &UserRepository{}
+}
+
+// Create inserts a new user.
+func (r *UserRepository) Create(user *model.User) error {
+	return db.GetDB().Create(user).Error
+}
+
+// FindByEmail looks up a user by email.
+func (r *UserRepository) FindByEmail(email string) (*model.User, error) {
+	var user model.User
+	if err := db.GetDB().Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByID looks up a user by primary key.
+func (r *UserRepository) FindByID(id uint) (*model.User, error) {
+	var user model.User
+	if err := db.GetDB().First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindAll returns every registered user.
+func (r *UserRepository) FindAll() ([]model.User, error) {
+	var users []model.User
+	if err := db.GetDB().Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"inkwell-backend-V2.0/internal/llm"
+	"inkwell-backend-V2.0/internal/model"
+	"inkwell-backend-V2.0/internal/repository"
+)
+
+// questionsPerAssessment is how many grammar questions CreateAssessment asks
+// the active LLM provider for.
+const questionsPerAssessment = 5
+
+// AssessmentService creates grammar assessments, generating their questions
+// via an llm.Provider, and records submitted answers.
+type AssessmentService struct {
+	assessmentRepo *repository.AssessmentRepository
+	provider       llm.Provider
+	// shutdownCtx is canceled when the server begins its shutdown drain
+	// window, aborting any LLM call still running on its behalf.
+	shutdownCtx context.Context
+}
+
+// NewAssessmentService builds an AssessmentService backed by assessmentRepo
+// that generates questions via provider. shutdownCtx should be canceled when
+// the server shuts down, to abort any in-flight LLM call.
+func NewAssessmentService(assessmentRepo *repository.AssessmentRepository, provider llm.Provider, shutdownCtx context.Context) *AssessmentService {
+	return &AssessmentService{assessmentRepo: assessmentRepo, provider: provider, shutdownCtx: shutdownCtx}
+}
+
+// requestContext derives a context from ctx that also ends if shutdownCtx is
+// canceled first, so a request in flight when the server starts shutting
+// down doesn't keep an LLM call running past the drain window.
+func (s *AssessmentService) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	merged, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-s.shutdownCtx.Done():
+			cancel()
+		case <-merged.Done():
+		}
+	}()
+	return merged, cancel
+}
+
+// CreateAssessment generates a new assessment on topic and persists it along
+// with its generated questions.
+func (s *AssessmentService) CreateAssessment(ctx context.Context, topic string) (*model.Assessment, []model.Question, error) {
+	ctx, cancel := s.requestContext(ctx)
+	defer cancel()
+
+	resp, err := s.provider.Generate(ctx, llm.GenerateRequest{Prompt: buildQuestionsPrompt(topic)})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate questions: %w", err)
+	}
+
+	return s.finalizeAssessment(topic, resp.Text)
+}
+
+// StreamQuestions asks the active provider to generate questionsPerAssessment
+// grammar questions on topic and returns the raw token stream. Once the
+// stream is exhausted, pass the concatenation of every Chunk.Text to
+// FinalizeAssessment to parse and persist the assessment.
+func (s *AssessmentService) StreamQuestions(ctx context.Context, topic string) (<-chan llm.Chunk, error) {
+	ctx, cancel := s.requestContext(ctx)
+	chunks, err := s.provider.Stream(ctx, llm.GenerateRequest{Prompt: buildQuestionsPrompt(topic)})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return cancelOnClose(chunks, cancel), nil
+}
+
+// FinalizeAssessment parses the full text produced by StreamQuestions (or an
+// equivalent non-streamed Generate call) into questions and persists the
+// resulting assessment.
+func (s *AssessmentService) FinalizeAssessment(topic, text string) (*model.Assessment, []model.Question, error) {
+	return s.finalizeAssessment(topic, text)
+}
+
+// GetAssessmentBySessionID loads an assessment and its questions.
+func (s *AssessmentService) GetAssessmentBySessionID(sessionID string) (*model.Assessment, error) {
+	return s.assessmentRepo.GetBySessionID(sessionID)
+}
+
+// SaveAnswer persists a submitted answer.
+func (s *AssessmentService) SaveAnswer(answer *model.Answer) (*model.Answer, error) {
+	return s.assessmentRepo.SaveAnswer(answer)
+}
+
+// StreamFeedback asks the active provider to explain, in a sentence or two,
+// why answer is correct or incorrect for question, and returns the raw
+// token stream.
+func (s *AssessmentService) StreamFeedback(ctx context.Context, question *model.Question, answer string, isCorrect bool) (<-chan llm.Chunk, error) {
+	verdict := "incorrect"
+	if isCorrect {
+		verdict = "correct"
+	}
+
+	prompt := fmt.Sprintf(
+		"A student was asked: %q. They answered %q. The correct answer is %q, "+
+			"so their answer was %s. In one or two sentences, explain why.",
+		question.Text, answer, question.CorrectAnswer, verdict,
+	)
+
+	ctx, cancel := s.requestContext(ctx)
+	chunks, err := s.provider.Stream(ctx, llm.GenerateRequest{Prompt: prompt})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return cancelOnClose(chunks, cancel), nil
+}
+
+// cancelOnClose passes chunks through unchanged, calling cancel once the
+// upstream channel closes so requestContext's watcher goroutine doesn't
+// outlive the stream.
+func cancelOnClose(chunks <-chan llm.Chunk, cancel context.CancelFunc) <-chan llm.Chunk {
+	out := make(chan llm.Chunk)
+	go func() {
+		defer close(out)
+		defer cancel()
+		for chunk := range chunks {
+			out <- chunk
+		}
+	}()
+	return out
+}
+
+func (s *AssessmentService) finalizeAssessment(topic, text string) (*model.Assessment, []model.Question, error) {
+	questions, err := parseQuestions(text)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse generated questions: %w", err)
+	}
+
+	sessionID, err := newSessionID()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate session id: %w", err)
+	}
+
+	assessment := &model.Assessment{
+		SessionID: sessionID,
+		Topic:     topic,
+		Questions: questions,
+	}
+
+	if err := s.assessmentRepo.Create(assessment); err != nil {
+		return nil, nil, err
+	}
+
+	return assessment, assessment.Questions, nil
+}
+
+// buildQuestionsPrompt asks the provider for questionsPerAssessment grammar
+// questions on topic, one "Question: ... | Answer: ..." pair per line.
+func buildQuestionsPrompt(topic string) string {
+	return fmt.Sprintf(
+		"Write %d short grammar quiz questions about %q. "+
+			"Reply with exactly one question per line, formatted as "+
+			"`Question: <question> | Answer: <correct answer>`.",
+		questionsPerAssessment, topic,
+	)
+}
+
+// parseQuestions parses the "Question: ... | Answer: ..." lines produced by
+// a prompt built with buildQuestionsPrompt.
+func parseQuestions(text string) ([]model.Question, error) {
+	var questions []model.Question
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(line, "Question: "), " | Answer: ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		questions = append(questions, model.Question{
+			Text:          strings.TrimSpace(parts[0]),
+			CorrectAnswer: strings.TrimSpace(parts[1]),
+		})
+	}
+
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("provider returned no parsable questions")
+	}
+
+	return questions, nil
+}
+
+func newSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
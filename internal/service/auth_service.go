@@ -0,0 +1,42 @@
+package service
+
+import (
+	"errors"
+
+	"inkwell-backend-V2.0/internal/model"
+	"inkwell-backend-V2.0/internal/repository"
+)
+
+// AuthService handles registration and login for local (email+authhash)
+// accounts.
+type AuthService struct {
+	userRepo *repository.UserRepository
+}
+
+// NewAuthService builds an AuthService backed by userRepo.
+func NewAuthService(userRepo *repository.UserRepository) *AuthService {
+	return &AuthService{userRepo: userRepo}
+}
+
+// Register creates a new user account.
+func (s *AuthService) Register(user *model.User) error {
+	if existing, _ := s.userRepo.FindByEmail(user.Email); existing != nil {
+		return errors.New("a user with this email already exists")
+	}
+	if user.Role == "" {
+		user.Role = "student"
+	}
+	return s.userRepo.Create(user)
+}
+
+// Login verifies the email/authhash pair and returns the matching user.
+func (s *AuthService) Login(email, authHash string) (*model.User, error) {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		return nil, errors.New("invalid email or password")
+	}
+	if user.AuthHash != authHash {
+		return nil, errors.New("invalid email or password")
+	}
+	return user, nil
+}
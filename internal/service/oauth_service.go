@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"inkwell-backend-V2.0/internal/authtoken"
+	"inkwell-backend-V2.0/internal/config"
+	"inkwell-backend-V2.0/internal/model"
+	"inkwell-backend-V2.0/internal/repository"
+)
+
+// githubUserEmailsURL is GitHub's endpoint for a user's registered emails,
+// used as a fallback when /user doesn't return one (it doesn't unless the
+// account's email is public).
+const githubUserEmailsURL = "https://api.github.com/user/emails"
+
+// sessionTTL is how long an OAuth-issued session JWT stays valid.
+const sessionTTL = 24 * time.Hour
+
+// OAuthService drives the external-provider login flow: building the
+// authorize URL, exchanging the callback code, and upserting the resulting
+// model.User/model.OAuthIdentity pair.
+type OAuthService struct {
+	providers    map[string]config.OAuthProviderConfig
+	userRepo     *repository.UserRepository
+	identityRepo *repository.OAuthIdentityRepository
+	jwtSecret    string
+	httpClient   *http.Client
+}
+
+// NewOAuthService builds an OAuthService for the configured providers.
+func NewOAuthService(providers []config.OAuthProviderConfig, userRepo *repository.UserRepository, identityRepo *repository.OAuthIdentityRepository, jwtSecret string) *OAuthService {
+	byName := make(map[string]config.OAuthProviderConfig, len(providers))
+	for _, p := range providers {
+		byName[p.Name] = p
+	}
+
+	return &OAuthService{
+		providers:    byName,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		jwtSecret:    jwtSecret,
+		httpClient:   &http.Client{},
+	}
+}
+
+func (s *OAuthService) provider(name string) (config.OAuthProviderConfig, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return config.OAuthProviderConfig{}, fmt.Errorf("unknown oauth provider %q", name)
+	}
+	return p, nil
+}
+
+// AuthorizeURL builds the URL to send the user to in order to start
+// provider's login flow, with state round-tripped for CSRF protection.
+func (s *OAuthService) AuthorizeURL(providerName, state string) (string, error) {
+	p, err := s.provider(providerName)
+	if err != nil {
+		return "", err
+	}
+
+	q := url.Values{}
+	q.Set("client_id", p.ClientID)
+	q.Set("redirect_uri", p.RedirectURL)
+	q.Set("response_type", "code")
+	q.Set("state", state)
+	q.Set("scope", strings.Join(p.Scopes, " "))
+
+	return p.AuthURL + "?" + q.Encode(), nil
+}
+
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+type oauthUserInfo struct {
+	Subject string `json:"sub"`
+	Email   string `json:"email"`
+}
+
+// HandleCallback exchanges code for an access token, fetches the provider's
+// userinfo, and upserts the matching model.User and model.OAuthIdentity.
+func (s *OAuthService) HandleCallback(ctx context.Context, providerName, code string) (*model.User, error) {
+	p, err := s.provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.exchangeCode(ctx, p, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange oauth code: %w", err)
+	}
+
+	info, err := s.fetchUserInfo(ctx, p, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch oauth userinfo: %w", err)
+	}
+
+	return s.upsertUser(providerName, info)
+}
+
+func (s *OAuthService) exchangeCode(ctx context.Context, p config.OAuthProviderConfig, code string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("client_id", p.ClientID)
+	form.Set("client_secret", p.ClientSecret)
+	form.Set("redirect_uri", p.RedirectURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out oauthTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", err
+	}
+	if out.AccessToken == "" {
+		return "", fmt.Errorf("token endpoint returned no access token")
+	}
+
+	return out.AccessToken, nil
+}
+
+func (s *OAuthService) fetchUserInfo(ctx context.Context, p config.OAuthProviderConfig, accessToken string) (*oauthUserInfo, error) {
+	if p.Name == "github" {
+		return s.fetchGitHubUserInfo(ctx, accessToken)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.UserInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var info oauthUserInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	if info.Subject == "" {
+		return nil, fmt.Errorf("userinfo response had no subject")
+	}
+
+	return &info, nil
+}
+
+// githubUser is the subset of GitHub's GET /user response Inkwell needs.
+// Unlike Google, GitHub has no OIDC "sub" claim (use the numeric id instead)
+// and commonly omits email unless the account has made one public.
+type githubUser struct {
+	ID    int    `json:"id"`
+	Email string `json:"email"`
+}
+
+// githubEmail is one entry of GitHub's GET /user/emails response.
+type githubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+// fetchGitHubUserInfo fetches the authenticated GitHub user, falling back to
+// /user/emails for a verified primary email when /user doesn't return one.
+func (s *OAuthService) fetchGitHubUserInfo(ctx context.Context, accessToken string) (*oauthUserInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/user", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var user githubUser
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, fmt.Errorf("userinfo response had no id")
+	}
+
+	email := user.Email
+	if email == "" {
+		email, err = s.fetchGitHubPrimaryEmail(ctx, accessToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &oauthUserInfo{Subject: strconv.Itoa(user.ID), Email: email}, nil
+}
+
+// fetchGitHubPrimaryEmail returns the user's verified primary email, or ""
+// if none is verified.
+func (s *OAuthService) fetchGitHubPrimaryEmail(ctx context.Context, accessToken string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, githubUserEmailsURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var emails []githubEmail
+	if err := json.NewDecoder(resp.Body).Decode(&emails); err != nil {
+		return "", err
+	}
+	for _, e := range emails {
+		if e.Primary && e.Verified {
+			return e.Email, nil
+		}
+	}
+
+	return "", nil
+}
+
+func (s *OAuthService) upsertUser(providerName string, info *oauthUserInfo) (*model.User, error) {
+	if identity, err := s.identityRepo.FindByProviderSubject(providerName, info.Subject); err == nil {
+		return s.userRepo.FindByID(identity.UserID)
+	}
+
+	// Some providers (e.g. GitHub) omit email from userinfo unless a scope
+	// explicitly requests it. Refuse to look up or create a model.User on an
+	// empty email: it collides with the unique email index on the second
+	// such user and would otherwise link distinct OAuth accounts together.
+	if info.Email == "" {
+		return nil, fmt.Errorf("%s did not return an email for this account", providerName)
+	}
+
+	user, err := s.userRepo.FindByEmail(info.Email)
+	if err != nil {
+		user = &model.User{Email: info.Email, Role: "student"}
+		if err := s.userRepo.Create(user); err != nil {
+			return nil, err
+		}
+	}
+
+	identity := &model.OAuthIdentity{Provider: providerName, Subject: info.Subject, UserID: user.ID}
+	if err := s.identityRepo.Create(identity); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// IssueSessionToken issues a signed JWT for user, suitable for storing in an
+// httpOnly session cookie.
+func (s *OAuthService) IssueSessionToken(user *model.User) (string, error) {
+	return authtoken.Issue(s.jwtSecret, user.ID, user.Role, sessionTTL)
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"inkwell-backend-V2.0/internal/model"
+	"inkwell-backend-V2.0/internal/repository"
+)
+
+// StoryService exposes read operations over reading content.
+type StoryService struct {
+	storyRepo *repository.StoryRepository
+}
+
+// NewStoryService builds a StoryService backed by storyRepo.
+func NewStoryService(storyRepo *repository.StoryRepository) *StoryService {
+	return &StoryService{storyRepo: storyRepo}
+}
+
+// GetStories returns every story.
+func (s *StoryService) GetStories() ([]model.Story, error) {
+	return s.storyRepo.FindAll()
+}
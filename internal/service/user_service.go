@@ -0,0 +1,21 @@
+package service
+
+import (
+	"inkwell-backend-V2.0/internal/model"
+	"inkwell-backend-V2.0/internal/repository"
+)
+
+// UserService exposes read operations over registered accounts.
+type UserService struct {
+	userRepo *repository.UserRepository
+}
+
+// NewUserService builds a UserService backed by userRepo.
+func NewUserService(userRepo *repository.UserRepository) *UserService {
+	return &UserService{userRepo: userRepo}
+}
+
+// GetAllUsers returns every registered user.
+func (s *UserService) GetAllUsers() ([]model.User, error) {
+	return s.userRepo.FindAll()
+}
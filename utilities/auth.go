@@ -0,0 +1,77 @@
+package utilities
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+
+	"inkwell-backend-V2.0/internal/authtoken"
+	"inkwell-backend-V2.0/internal/db"
+	"inkwell-backend-V2.0/internal/model"
+)
+
+// SessionCookieName is the httpOnly cookie holding a JWT issued after a
+// successful OAuth login.
+const SessionCookieName = "inkwell_session"
+
+// openRoutes don't require authentication.
+var openRoutes = []string{"/auth/", "/stories"}
+
+// AuthMiddleware accepts a server-side session (set by /auth/login), a
+// session JWT (set as the SessionCookieName cookie after an OAuth login), or
+// the legacy X-User-Email / X-Auth-Hash headers, and stores the matching
+// user id and role on the Gin context. sessions.Sessions must be registered
+// ahead of this middleware: sessions.Default panics without it.
+func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, route := range openRoutes {
+			if strings.HasPrefix(c.Request.URL.Path, route) {
+				c.Next()
+				return
+			}
+		}
+
+		session := sessions.Default(c)
+		if userID, ok := session.Get(SessionUserIDKey).(uint); ok {
+			c.Set("user_id", userID)
+			c.Set("role", session.Get(SessionRoleKey))
+			c.Next()
+			return
+		}
+
+		if cookie, err := c.Cookie(SessionCookieName); err == nil && cookie != "" {
+			claims, err := authtoken.Parse(jwtSecret, cookie)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid session"})
+				c.Abort()
+				return
+			}
+
+			c.Set("user_id", claims.UserID)
+			c.Set("role", claims.Role)
+			c.Next()
+			return
+		}
+
+		email := c.GetHeader("X-User-Email")
+		authHash := c.GetHeader("X-Auth-Hash")
+		if email == "" || authHash == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			c.Abort()
+			return
+		}
+
+		var user model.User
+		if err := db.GetDB().Where("email = ? AND auth_hash = ?", email, authHash).First(&user).Error; err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", user.ID)
+		c.Set("role", user.Role)
+		c.Next()
+	}
+}
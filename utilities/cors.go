@@ -0,0 +1,27 @@
+// Package utilities holds Gin middleware shared across Inkwell's routes.
+package utilities
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSMiddleware allows the Inkwell frontend, served from a different
+// origin during development, to call this API. It allows any origin and does
+// not allow credentials, so browsers do not send cookies on cross-origin
+// requests.
+func CORSMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-CSRF-Token, X-User-Email, X-Auth-Hash")
+
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package utilities
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+// csrfProtectedPrefixes are the route groups that require a matching
+// X-CSRF-Token header on every non-GET request.
+var csrfProtectedPrefixes = []string{"/assessments", "/stories"}
+
+// CSRFMiddleware validates the X-CSRF-Token header against the token stored
+// in the session at login, for non-GET requests under a protected prefix.
+func CSRFMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet {
+			c.Next()
+			return
+		}
+
+		protected := false
+		for _, prefix := range csrfProtectedPrefixes {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				protected = true
+				break
+			}
+		}
+		if !protected {
+			c.Next()
+			return
+		}
+
+		session := sessions.Default(c)
+		expected, _ := session.Get(CSRFSessionKey).(string)
+		if expected == "" || c.GetHeader("X-CSRF-Token") != expected {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid or missing CSRF token"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
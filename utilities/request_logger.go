@@ -0,0 +1,62 @@
+package utilities
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Gin context keys set by RequestLogger.
+const (
+	RequestIDContextKey = "request_id"
+	loggerContextKey    = "logger"
+)
+
+// RequestLogger generates a request id, echoes it back on the X-Request-ID
+// response header, and emits one structured log line per request using
+// logger. Install it ahead of every route so the id and logger are
+// available to downstream handlers and middleware.
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := uuid.NewString()
+		c.Set(RequestIDContextKey, requestID)
+		c.Writer.Header().Set("X-Request-ID", requestID)
+
+		requestLogger := logger.With("request_id", requestID)
+		c.Set(loggerContextKey, requestLogger)
+
+		start := time.Now()
+		c.Next()
+
+		userID, _ := c.Get("user_id")
+
+		requestLogger.Info("http_request",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"duration_ms", time.Since(start).Milliseconds(),
+			"bytes", c.Writer.Size(),
+			"client_ip", c.ClientIP(),
+			"user_id", userID,
+		)
+	}
+}
+
+// RequestID returns the request id RequestLogger generated for c, or "" if
+// RequestLogger hasn't run.
+func RequestID(c *gin.Context) string {
+	return c.GetString(RequestIDContextKey)
+}
+
+// LoggerFromContext returns the request-scoped logger RequestLogger stored
+// on c, falling back to slog.Default() if RequestLogger hasn't run.
+func LoggerFromContext(c *gin.Context) *slog.Logger {
+	if value, ok := c.Get(loggerContextKey); ok {
+		if logger, ok := value.(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
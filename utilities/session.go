@@ -0,0 +1,24 @@
+package utilities
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Session keys shared between the auth handlers that populate the session
+// and the middleware that reads it.
+const (
+	SessionUserIDKey = "user_id"
+	SessionRoleKey   = "role"
+	CSRFSessionKey   = "csrf_token"
+)
+
+// NewCSRFToken generates a random token to store in the session and hand
+// back to the client to echo on the X-CSRF-Token header.
+func NewCSRFToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}